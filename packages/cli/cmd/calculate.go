@@ -1,6 +1,14 @@
 package cmd
 
 import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"tax-cli/pkg/rules"
 	"tax-cli/ui"
 
 	"github.com/spf13/cobra"
@@ -10,14 +18,185 @@ import (
    COMMAND
 ======================= */
 
+var (
+	batchInput  string
+	jsonInput   string
+	rulesetFlag string
+
+	oneShotMode       string
+	oneShotPeriod     string
+	oneShotIncome     float64
+	oneShotDependents int
+	oneShotInsurance  bool
+	oneShotJSON       bool
+)
+
 var calculateCmd = &cobra.Command{
 	Use:   "calculate",
 	Short: "Tính thuế thu nhập cá nhân Việt Nam (interactive)",
 	RunE: func(cmd *cobra.Command, args []string) error {
-		return ui.RunTaxTUI()
+		r, err := rules.Load(rulesetFlag)
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case jsonInput != "":
+			return runJSONBatch(jsonInput, r)
+		case oneShotJSON:
+			return runOneShot(r)
+		case batchInput != "":
+			return runBatch(batchInput, r)
+		default:
+			return ui.RunTaxTUI(r)
+		}
 	},
 }
 
 func init() {
+	calculateCmd.Flags().StringVar(&batchInput, "batch", "", "Tính lương hàng loạt từ file CSV (Name,Mode,Period,Income,Dependents,Insurance,BankName,BankCard)")
+	calculateCmd.Flags().StringVar(&rulesetFlag, "rules", rules.DefaultRuleset, "Bộ quy tắc thuế/bảo hiểm áp dụng (xem: tax rules list)")
+
+	calculateCmd.Flags().StringVar(&oneShotMode, "mode", "gross", "Chế độ lương: gross hoặc net (dùng với --json)")
+	calculateCmd.Flags().StringVar(&oneShotPeriod, "period", "monthly", "Kỳ tính: monthly hoặc annual (dùng với --json)")
+	calculateCmd.Flags().Float64Var(&oneShotIncome, "income", 0, "Thu nhập (VND) (dùng với --json)")
+	calculateCmd.Flags().IntVar(&oneShotDependents, "dependents", 0, "Số người phụ thuộc (dùng với --json)")
+	calculateCmd.Flags().BoolVar(&oneShotInsurance, "insurance", false, "Có tính bảo hiểm bắt buộc hay không (dùng với --json)")
+	calculateCmd.Flags().BoolVar(&oneShotJSON, "json", false, "Tính một lần và in kết quả dạng JSON, bỏ qua giao diện tương tác")
+	calculateCmd.Flags().StringVar(&jsonInput, "input", "", "Tính hàng loạt từ file JSON chứa mảng input, in ra mảng kết quả JSON")
+
 	rootCmd.AddCommand(calculateCmd)
 }
+
+/* =======================
+   JSON MODE
+======================= */
+
+// jsonCalcInput is the shape accepted both as individual --mode/--period/...
+// flags and as one element of the array read via --input.
+type jsonCalcInput struct {
+	Mode       string  `json:"mode"`
+	Period     string  `json:"period"`
+	Income     float64 `json:"income"`
+	Dependents int     `json:"dependents"`
+	Insurance  bool    `json:"insurance"`
+}
+
+// jsonCalcResult is the non-interactive, script-friendly output: the
+// computed amounts plus the effective marginal and average tax rates.
+type jsonCalcResult struct {
+	Gross             float64           `json:"gross"`
+	Net               float64           `json:"net"`
+	Tax               float64           `json:"tax"`
+	Breakdown         []ui.TaxBreakdown `json:"breakdown"`
+	EmployeeInsurance []ui.InsuranceRow `json:"employeeInsurance"`
+	EmployerInsurance []ui.InsuranceRow `json:"employerInsurance"`
+	MarginalRate      float64           `json:"marginalRate"`
+	AverageRate       float64           `json:"averageRate"`
+}
+
+func toJSONResult(r ui.CalcResult) jsonCalcResult {
+	marginal := 0.0
+	if n := len(r.Breakdown); n > 0 {
+		marginal = r.Breakdown[n-1].Rate
+	}
+	average := 0.0
+	if r.Gross > 0 {
+		average = r.Tax / r.Gross
+	}
+
+	return jsonCalcResult{
+		Gross:             r.Gross,
+		Net:               r.Net,
+		Tax:               r.Tax,
+		Breakdown:         r.Breakdown,
+		EmployeeInsurance: r.EmployeeInsurance,
+		EmployerInsurance: r.EmployerInsurance,
+		MarginalRate:      marginal,
+		AverageRate:       average,
+	}
+}
+
+func runOneShot(r rules.Rules) error {
+	result := ui.Compute(ui.SalaryMode(oneShotMode), ui.Period(oneShotPeriod), oneShotIncome, oneShotDependents, oneShotInsurance, r)
+	return printJSON(toJSONResult(result))
+}
+
+// runJSONBatch reads an array of jsonCalcInput from path, computes each
+// under the given ruleset, and prints an array of jsonCalcResult — the
+// composable path for driving tax-cli from another program or pipeline.
+func runJSONBatch(path string, r rules.Rules) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	var inputs []jsonCalcInput
+	if err := json.Unmarshal(data, &inputs); err != nil {
+		return err
+	}
+
+	results := make([]jsonCalcResult, 0, len(inputs))
+	for _, in := range inputs {
+		result := ui.Compute(ui.SalaryMode(in.Mode), ui.Period(in.Period), in.Income, in.Dependents, in.Insurance, r)
+		results = append(results, toJSONResult(result))
+	}
+
+	return printJSON(results)
+}
+
+func printJSON(v interface{}) error {
+	out, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(out))
+	return nil
+}
+
+/* =======================
+   BATCH MODE
+======================= */
+
+// runBatch reads a CSV of employees, calculates each one under the given
+// ruleset, and writes a single workbook with a per-employee sheet plus a
+// company-wide totals sheet via ui.ExportBatchXLSX.
+func runBatch(path string, r rules.Rules) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	rows, err := csv.NewReader(file).ReadAll()
+	if err != nil {
+		return err
+	}
+
+	var employees []ui.BatchEmployee
+	for _, row := range rows {
+		if len(row) < 8 {
+			continue
+		}
+
+		mode := ui.SalaryMode(row[1])
+		period := ui.Period(row[2])
+		income, _ := strconv.ParseFloat(row[3], 64)
+		dependents, _ := strconv.Atoi(row[4])
+		insurance, _ := strconv.ParseBool(row[5])
+
+		result := ui.Compute(mode, period, income, dependents, insurance, r)
+		employees = append(employees, ui.BatchEmployee{
+			Employee: ui.Employee{Name: row[0], BankName: row[6], BankCard: row[7]},
+			Result:   result,
+		})
+	}
+
+	out := "payroll-batch.xlsx"
+	if err := ui.ExportBatchXLSX(out, employees, time.Now()); err != nil {
+		return err
+	}
+
+	fmt.Printf("Đã xuất %d nhân viên ra %s\n", len(employees), out)
+	return nil
+}
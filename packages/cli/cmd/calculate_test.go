@@ -0,0 +1,57 @@
+package cmd
+
+import (
+	"testing"
+
+	"tax-cli/pkg/rules"
+	"tax-cli/ui"
+)
+
+// TestToJSONResultStableShape asserts the --json/--input output shape
+// (gross/net/tax, bracket breakdown, and the insurance arrays) is stable
+// across repeated calls with the same input — in particular the
+// employeeInsurance/employerInsurance order, which used to follow Go's
+// randomized map iteration order via ui.insuranceRows.
+func TestToJSONResultStableShape(t *testing.T) {
+	r := rules.Default()
+	wantInsuranceOrder := []string{"BHTN", "BHXH", "BHYT"}
+
+	for i := 0; i < 10; i++ {
+		result := ui.Compute(ui.Gross, ui.Monthly, 20000000, 1, true, r)
+		got := toJSONResult(result)
+
+		if got.Gross != result.Gross || got.Net != result.Net || got.Tax != result.Tax {
+			t.Fatalf("run %d: toJSONResult dropped gross/net/tax: got %+v, from %+v", i, got, result)
+		}
+		if len(got.Breakdown) == 0 {
+			t.Fatalf("run %d: Breakdown is empty for a taxable income", i)
+		}
+
+		if names := insuranceNames(got.EmployeeInsurance); !equalStrings(names, wantInsuranceOrder) {
+			t.Errorf("run %d: EmployeeInsurance order = %v, want %v", i, names, wantInsuranceOrder)
+		}
+		if names := insuranceNames(got.EmployerInsurance); !equalStrings(names, wantInsuranceOrder) {
+			t.Errorf("run %d: EmployerInsurance order = %v, want %v", i, names, wantInsuranceOrder)
+		}
+	}
+}
+
+func insuranceNames(rows []ui.InsuranceRow) []string {
+	out := make([]string, len(rows))
+	for i, row := range rows {
+		out[i] = row.Name
+	}
+	return out
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,24 @@
+package cmd
+
+import (
+	"tax-cli/pkg/rules"
+	"tax-cli/ui"
+
+	"github.com/spf13/cobra"
+)
+
+/* =======================
+   COMMAND
+======================= */
+
+var finalizeCmd = &cobra.Command{
+	Use:   "finalize",
+	Short: "Quyết toán thuế thu nhập cá nhân năm (interactive)",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return ui.RunTaxFinalizeTUI(rules.Default())
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(finalizeCmd)
+}
@@ -0,0 +1,38 @@
+package cmd
+
+import (
+	"fmt"
+
+	"tax-cli/pkg/rules"
+
+	"github.com/spf13/cobra"
+)
+
+/* =======================
+   COMMAND
+======================= */
+
+var rulesCmd = &cobra.Command{
+	Use:   "rules",
+	Short: "Quản lý bộ quy tắc thuế/bảo hiểm theo quốc gia và năm hiệu lực",
+}
+
+var rulesListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "Liệt kê các bộ quy tắc khả dụng (mặc định và từ ~/.config/tax-cli/rules.yaml)",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		for _, name := range rules.List() {
+			r, err := rules.Load(name)
+			if err != nil {
+				return err
+			}
+			fmt.Printf("%-10s %s (hiệu lực từ %s)\n", name, r.Country, r.EffectiveFrom)
+		}
+		return nil
+	},
+}
+
+func init() {
+	rulesCmd.AddCommand(rulesListCmd)
+	rootCmd.AddCommand(rulesCmd)
+}
@@ -0,0 +1,134 @@
+// Package rules loads the jurisdiction/year PIT ruleset (brackets,
+// deductions, insurance rates) that used to be hardcoded constants in the
+// ui package, the same way paisa loads commodities and allocation targets
+// from paisa.yaml. Built-in rulesets are keyed by name (e.g. "VN-2024");
+// users can add or override rulesets via ~/.config/tax-cli/rules.yaml.
+package rules
+
+import (
+	"embed"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"gopkg.in/yaml.v3"
+)
+
+//go:embed data/*.yaml
+var builtinFS embed.FS
+
+var builtinFiles = map[string]string{
+	"VN-2020": "data/vn-2020.yaml",
+	"VN-2024": "data/vn-2024.yaml",
+	"VN-2026": "data/vn-2026.yaml",
+}
+
+// DefaultRuleset is used whenever a caller doesn't pick one explicitly.
+const DefaultRuleset = "VN-2024"
+
+// Bracket is one progressive PIT bracket: tax Rate applies to the slice of
+// taxable income up to Limit above the previous bracket's ceiling.
+type Bracket struct {
+	Limit float64 `yaml:"limit"`
+	Rate  float64 `yaml:"rate"`
+}
+
+// Rules is the full set of jurisdiction/year PIT parameters: brackets,
+// deductions, and insurance rates.
+type Rules struct {
+	Country            string             `yaml:"country"`
+	EffectiveFrom      string             `yaml:"effectiveFrom"`
+	PersonalDeduction  float64            `yaml:"personalDeduction"`
+	DependentDeduction float64            `yaml:"dependentDeduction"`
+	InsuranceCap       float64            `yaml:"insuranceCap"`
+	EmployeeInsurance  map[string]float64 `yaml:"employeeInsurance"`
+	EmployerInsurance  map[string]float64 `yaml:"employerInsurance"`
+	TaxBrackets        []Bracket          `yaml:"taxBrackets"`
+}
+
+// Default returns the built-in DefaultRuleset ("VN-2024").
+func Default() Rules {
+	r, err := Load(DefaultRuleset)
+	if err != nil {
+		panic(fmt.Sprintf("rules: default ruleset %q failed to load: %v", DefaultRuleset, err))
+	}
+	return r
+}
+
+// Load resolves a ruleset by name: a matching entry in
+// ~/.config/tax-cli/rules.yaml takes precedence over the built-in ruleset
+// of the same name.
+func Load(name string) (Rules, error) {
+	if overrides, err := loadOverrides(); err == nil {
+		if r, ok := overrides[name]; ok {
+			return r, nil
+		}
+	}
+
+	path, ok := builtinFiles[name]
+	if !ok {
+		return Rules{}, fmt.Errorf("rules: unknown ruleset %q (available: %v)", name, List())
+	}
+
+	data, err := builtinFS.ReadFile(path)
+	if err != nil {
+		return Rules{}, fmt.Errorf("rules: reading built-in ruleset %q: %w", name, err)
+	}
+
+	var r Rules
+	if err := yaml.Unmarshal(data, &r); err != nil {
+		return Rules{}, fmt.Errorf("rules: parsing built-in ruleset %q: %w", name, err)
+	}
+	return r, nil
+}
+
+// List returns every available ruleset name: built-ins plus anything
+// defined in ~/.config/tax-cli/rules.yaml, sorted.
+func List() []string {
+	seen := map[string]bool{}
+	for name := range builtinFiles {
+		seen[name] = true
+	}
+	if overrides, err := loadOverrides(); err == nil {
+		for name := range overrides {
+			seen[name] = true
+		}
+	}
+
+	names := make([]string, 0, len(seen))
+	for name := range seen {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// overridesPath returns ~/.config/tax-cli/rules.yaml.
+func overridesPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".config", "tax-cli", "rules.yaml"), nil
+}
+
+// loadOverrides parses the user's rules.yaml, a map of ruleset name to
+// Rules. A missing file is not an error; it simply yields no overrides.
+func loadOverrides() (map[string]Rules, error) {
+	path, err := overridesPath()
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var overrides map[string]Rules
+	if err := yaml.Unmarshal(data, &overrides); err != nil {
+		return nil, fmt.Errorf("rules: parsing %s: %w", path, err)
+	}
+	return overrides, nil
+}
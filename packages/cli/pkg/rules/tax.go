@@ -0,0 +1,94 @@
+package rules
+
+// Period and the tax-calculation helpers below used to live in the ui
+// package, but pkg/tax needs them too (to annualize a Rules' brackets for
+// finalization) and cannot import ui, since ui imports pkg/tax for the
+// finalize flow. They live here, the one package both ui and pkg/tax already import, and
+// ui re-exports them as aliases so its existing call sites are unaffected.
+
+// Period is the interval a salary figure or tax table is expressed over.
+type Period string
+
+const (
+	Monthly Period = "monthly"
+	Annual  Period = "annual"
+)
+
+// TaxBreakdown is the tax due for one bracket: the rate, how much of the
+// taxable base fell into that bracket, and the resulting tax.
+type TaxBreakdown struct {
+	Rate    float64
+	Taxable float64
+	Tax     float64
+}
+
+// SumRates adds up a ruleset's per-contribution insurance rates (e.g.
+// BHXH + BHYT + BHTN) into the single combined rate applied to the base.
+func SumRates(r map[string]float64) float64 {
+	s := 0.0
+	for _, v := range r {
+		s += v
+	}
+	return s
+}
+
+// ClampInsuranceBase caps gross income at the ruleset's insurance cap, or
+// returns 0 if insurance is disabled.
+func ClampInsuranceBase(gross float64, enabled bool, cap float64) float64 {
+	if !enabled {
+		return 0
+	}
+	if gross > cap {
+		return cap
+	}
+	return gross
+}
+
+// annualizeBrackets scales every bracket limit by 12 so a monthly table can
+// be reused to tax an annualized base (Vietnam's finalization rules apply
+// the same progressive rates, just over a 12x wider band per bracket).
+func annualizeBrackets(brackets []Bracket) []Bracket {
+	out := make([]Bracket, len(brackets))
+	for i, b := range brackets {
+		out[i] = Bracket{Limit: b.Limit * 12, Rate: b.Rate}
+	}
+	return out
+}
+
+// CalculateTax applies brackets to taxable income for the given period.
+// Callers pass the monthly bracket table regardless of period; for Annual
+// it is transparently scaled via annualizeBrackets.
+func CalculateTax(taxable float64, brackets []Bracket, period Period) ([]TaxBreakdown, float64) {
+	if period == Annual {
+		brackets = annualizeBrackets(brackets)
+	}
+
+	var out []TaxBreakdown
+	remain := taxable
+	total := 0.0
+
+	for _, b := range brackets {
+		if remain <= 0 {
+			break
+		}
+		apply := Min(b.Limit, remain)
+		tax := apply * b.Rate
+		out = append(out, TaxBreakdown{b.Rate, apply, tax})
+		total += tax
+		remain -= apply
+	}
+	return out, total
+}
+
+func Min(a, b float64) float64 {
+	if a < b {
+		return a
+	}
+	return b
+}
+func Max(a, b float64) float64 {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,98 @@
+// Package tax implements Vietnam's annual PIT finalization ("quyết toán
+// thuế thu nhập cá nhân"), as opposed to the per-month withholding math in
+// the ui package. It aggregates twelve months of gross income plus one-off
+// items into an annualized taxable base, taxes it once, and compares the
+// result against tax already withheld each month.
+package tax
+
+import (
+	"tax-cli/pkg/rules"
+)
+
+/* =======================
+   DOMAIN TYPES
+======================= */
+
+// MonthRecord is one month of a multi-employer annual reconciliation: the
+// gross paid and the PIT the employer already withheld on it.
+type MonthRecord struct {
+	Month       int
+	Gross       float64
+	WithheldTax float64
+}
+
+// OneOff is a non-recurring item such as 13th-month salary, Tết bonus, KPI
+// bonus, or an allowance. Taxable controls whether it is added to the
+// annual taxable base (lunch allowance up to the statutory cap, for
+// example, is not).
+type OneOff struct {
+	Name    string
+	Amount  float64
+	Taxable bool
+}
+
+// Input gathers everything needed to finalize one employee's annual PIT.
+type Input struct {
+	Months     []MonthRecord
+	OneOffs    []OneOff
+	Dependents int
+	Insurance  bool
+	Rules      rules.Rules
+}
+
+// Result is the outcome of a finalization: the annual tax actually due
+// versus what was withheld across the year.
+type Result struct {
+	TotalGross    float64
+	TotalOneOffs  float64 // sum of every OneOff, taxable or not
+	TotalTaxable  float64
+	AnnualTax     float64
+	Breakdown     []rules.TaxBreakdown
+	TotalWithheld float64
+	Balance       float64 // TotalWithheld - AnnualTax; positive = refund, negative = additional payment
+}
+
+/* =======================
+   FINALIZATION
+======================= */
+
+// Finalize computes the annual reconciliation for one employee: it sums
+// the twelve monthly gross figures plus the taxable share of one-off items
+// into the taxable base, subtracts the annual personal/dependent
+// deductions and the insurance actually withheld each month, taxes the
+// resulting base on the annualized bracket table, and nets that against
+// tax already withheld. Result.TotalOneOffs reports every one-off
+// received, taxable or not.
+func Finalize(in Input) Result {
+	r := in.Rules
+
+	var totalGross, totalInsurance, totalWithheld float64
+	for _, m := range in.Months {
+		totalGross += m.Gross
+		totalInsurance += rules.ClampInsuranceBase(m.Gross, in.Insurance, r.InsuranceCap) * rules.SumRates(r.EmployeeInsurance)
+		totalWithheld += m.WithheldTax
+	}
+
+	var totalOneOffs, taxableOneOffs float64
+	for _, o := range in.OneOffs {
+		totalOneOffs += o.Amount
+		if o.Taxable {
+			taxableOneOffs += o.Amount
+		}
+	}
+
+	deductions := 12*r.PersonalDeduction + 12*float64(in.Dependents)*r.DependentDeduction + totalInsurance
+	taxable := rules.Max(0, totalGross+taxableOneOffs-deductions)
+
+	breakdown, tax := rules.CalculateTax(taxable, r.TaxBrackets, rules.Annual)
+
+	return Result{
+		TotalGross:    totalGross,
+		TotalOneOffs:  totalOneOffs,
+		TotalTaxable:  taxable,
+		AnnualTax:     tax,
+		Breakdown:     breakdown,
+		TotalWithheld: totalWithheld,
+		Balance:       totalWithheld - tax,
+	}
+}
@@ -0,0 +1,265 @@
+package ui
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/xuri/excelize/v2"
+)
+
+/* =======================
+   SINGLE EMPLOYEE EXPORT
+======================= */
+
+// Employee identifies who a payroll export row belongs to, beyond the pure
+// CalcResult numbers: name and the bank details needed for the "Bank"
+// sheet used to upload salaries directly to a Vietnamese bank.
+type Employee struct {
+	Name     string
+	BankName string
+	BankCard string
+}
+
+// exportXLSX writes the single-employee result collected by the TUI flow to
+// a 4-sheet workbook: Summary, TaxBrackets, Insurance, Bank.
+func exportXLSX(m model) error {
+	r := Compute(m.mode, m.period, m.income, m.dependents, m.insurance, m.rules)
+	emp := Employee{Name: "Employee", BankName: m.bankName, BankCard: m.bankCard}
+	return ExportXLSX("pit-vietnam.xlsx", emp, r, time.Now())
+}
+
+// ExportXLSX builds the 4-sheet single-employee payroll workbook described
+// above and writes it to path.
+func ExportXLSX(path string, emp Employee, r CalcResult, month time.Time) error {
+	f := excelize.NewFile()
+	defer f.Close()
+
+	if err := writeSummarySheet(f, "Summary", emp, r); err != nil {
+		return err
+	}
+	if err := writeTaxBracketsSheet(f, "TaxBrackets", r); err != nil {
+		return err
+	}
+	if err := writeInsuranceSheet(f, "Insurance", r); err != nil {
+		return err
+	}
+	if err := writeBankSheet(f, "Bank", emp, r, month); err != nil {
+		return err
+	}
+
+	f.DeleteSheet("Sheet1")
+	f.SetActiveSheet(0)
+
+	return f.SaveAs(path)
+}
+
+func writeSummarySheet(f *excelize.File, sheet string, emp Employee, r CalcResult) error {
+	if _, err := f.NewSheet(sheet); err != nil {
+		return fmt.Errorf("ui: creating sheet %q: %w", sheet, err)
+	}
+	rows := [][]interface{}{
+		{"Employee", emp.Name},
+		{"Gross", r.Gross},
+		{"Net", r.Net},
+		{"Tax", r.Tax},
+		{"Dependents", r.Dependents},
+		{"Employee Insurance Total", sumInsurance(r.EmployeeInsurance)},
+		{"Employer Insurance Total", sumInsurance(r.EmployerInsurance)},
+	}
+	return writeRows(f, sheet, []string{"Field", "Value"}, rows)
+}
+
+func writeTaxBracketsSheet(f *excelize.File, sheet string, r CalcResult) error {
+	if _, err := f.NewSheet(sheet); err != nil {
+		return fmt.Errorf("ui: creating sheet %q: %w", sheet, err)
+	}
+	rows := make([][]interface{}, 0, len(r.Breakdown))
+	for _, b := range r.Breakdown {
+		rows = append(rows, []interface{}{b.Rate, b.Taxable, b.Tax})
+	}
+	return writeRows(f, sheet, []string{"Rate", "Taxable", "Tax"}, rows)
+}
+
+func writeInsuranceSheet(f *excelize.File, sheet string, r CalcResult) error {
+	if _, err := f.NewSheet(sheet); err != nil {
+		return fmt.Errorf("ui: creating sheet %q: %w", sheet, err)
+	}
+	rows := make([][]interface{}, 0, len(r.EmployeeInsurance)+len(r.EmployerInsurance))
+	for _, row := range r.EmployeeInsurance {
+		rows = append(rows, []interface{}{"Employee", row.Name, row.Rate, row.Base, row.Amount})
+	}
+	for _, row := range r.EmployerInsurance {
+		rows = append(rows, []interface{}{"Employer", row.Name, row.Rate, row.Base, row.Amount})
+	}
+	return writeRows(f, sheet, []string{"Side", "Type", "Rate", "Base", "Amount"}, rows)
+}
+
+func writeBankSheet(f *excelize.File, sheet string, emp Employee, r CalcResult, month time.Time) error {
+	if _, err := f.NewSheet(sheet); err != nil {
+		return fmt.Errorf("ui: creating sheet %q: %w", sheet, err)
+	}
+	memo := fmt.Sprintf("Lương tháng %02d/%d", month.Month(), month.Year())
+	rows := [][]interface{}{
+		{emp.Name, emp.BankCard, r.Net, memo},
+	}
+	return writeRows(f, sheet, []string{"Name", "Bank Card", "Net Amount", "Memo"}, rows)
+}
+
+func sumInsurance(rows []InsuranceRow) float64 {
+	total := 0.0
+	for _, r := range rows {
+		total += r.Amount
+	}
+	return total
+}
+
+func writeRows(f *excelize.File, sheet string, header []string, rows [][]interface{}) error {
+	for col, h := range header {
+		cell, err := excelize.CoordinatesToCellName(col+1, 1)
+		if err != nil {
+			return err
+		}
+		if err := f.SetCellValue(sheet, cell, h); err != nil {
+			return fmt.Errorf("ui: writing %s!%s: %w", sheet, cell, err)
+		}
+	}
+	for i, row := range rows {
+		for col, v := range row {
+			cell, err := excelize.CoordinatesToCellName(col+1, i+2)
+			if err != nil {
+				return err
+			}
+			if err := f.SetCellValue(sheet, cell, v); err != nil {
+				return fmt.Errorf("ui: writing %s!%s: %w", sheet, cell, err)
+			}
+		}
+	}
+	return nil
+}
+
+/* =======================
+   SHEET NAMING
+======================= */
+
+// maxSheetNameLen is Excel's hard limit on worksheet name length.
+const maxSheetNameLen = 31
+
+// invalidSheetNameChars are the characters Excel forbids in a sheet name.
+const invalidSheetNameChars = `:\/?*[]`
+
+// sanitizeSheetName strips characters Excel forbids in a sheet name and
+// truncates to Excel's 31-character limit, so an employee name can't make
+// NewSheet/SetCellValue fail outright.
+func sanitizeSheetName(name string) string {
+	clean := strings.Map(func(r rune) rune {
+		if strings.ContainsRune(invalidSheetNameChars, r) {
+			return -1
+		}
+		return r
+	}, name)
+	clean = strings.TrimSpace(clean)
+	if clean == "" {
+		clean = "Sheet"
+	}
+	if runes := []rune(clean); len(runes) > maxSheetNameLen {
+		clean = string(runes[:maxSheetNameLen])
+	}
+	return clean
+}
+
+// uniqueSheetName sanitizes name and, if it collides with a sheet already
+// recorded in used, appends a numeric suffix (truncating further as needed
+// to stay within Excel's length limit) until it finds a free one. Two
+// employees with the same name must not collapse onto the same sheet and
+// silently overwrite each other.
+func uniqueSheetName(used map[string]bool, name string) string {
+	base := sanitizeSheetName(name)
+	candidate := base
+	for n := 2; used[candidate]; n++ {
+		suffix := "-" + strconv.Itoa(n)
+		trimmed := []rune(base)
+		if len(trimmed)+len(suffix) > maxSheetNameLen {
+			trimmed = trimmed[:maxSheetNameLen-len(suffix)]
+		}
+		candidate = string(trimmed) + suffix
+	}
+	used[candidate] = true
+	return candidate
+}
+
+/* =======================
+   BATCH EXPORT
+======================= */
+
+// BatchEmployee is one row of a --batch input CSV after it has been
+// calculated: who they are, their gross/net/tax breakdown, and their bank
+// details (the bank card is written to their per-employee sheet).
+type BatchEmployee struct {
+	Employee Employee
+	Result   CalcResult
+}
+
+// ExportBatchXLSX writes one workbook containing a per-employee sheet for
+// every entry plus a company-wide "Totals" sheet, so the output can be
+// uploaded to a bank directly or archived as the month's payroll record.
+func ExportBatchXLSX(path string, employees []BatchEmployee, month time.Time) error {
+	f := excelize.NewFile()
+	defer f.Close()
+
+	var totalGross, totalNet, totalTax, totalEmpIns, totalErIns float64
+	totalsRows := make([][]interface{}, 0, len(employees))
+	usedSheets := map[string]bool{}
+
+	for _, be := range employees {
+		sheet := uniqueSheetName(usedSheets, be.Employee.Name)
+		if _, err := f.NewSheet(sheet); err != nil {
+			return fmt.Errorf("ui: creating sheet %q: %w", sheet, err)
+		}
+		memo := fmt.Sprintf("Lương tháng %02d/%d", month.Month(), month.Year())
+		if err := writeRows(f, sheet,
+			[]string{"Field", "Value"},
+			[][]interface{}{
+				{"Gross", be.Result.Gross},
+				{"Net", be.Result.Net},
+				{"Tax", be.Result.Tax},
+				{"Bank Card", be.Employee.BankCard},
+				{"Memo", memo},
+			},
+		); err != nil {
+			return err
+		}
+
+		empIns := sumInsurance(be.Result.EmployeeInsurance)
+		erIns := sumInsurance(be.Result.EmployerInsurance)
+		totalGross += be.Result.Gross
+		totalNet += be.Result.Net
+		totalTax += be.Result.Tax
+		totalEmpIns += empIns
+		totalErIns += erIns
+
+		totalsRows = append(totalsRows, []interface{}{
+			be.Employee.Name, be.Result.Gross, be.Result.Net, be.Result.Tax, empIns, erIns,
+		})
+	}
+
+	totalsRows = append(totalsRows, []interface{}{
+		"COMPANY TOTAL", totalGross, totalNet, totalTax, totalEmpIns, totalErIns,
+	})
+
+	if _, err := f.NewSheet("Totals"); err != nil {
+		return fmt.Errorf("ui: creating sheet %q: %w", "Totals", err)
+	}
+	if err := writeRows(f, "Totals",
+		[]string{"Employee", "Gross", "Net", "Tax", "Employee Insurance", "Employer Insurance"},
+		totalsRows,
+	); err != nil {
+		return err
+	}
+
+	f.DeleteSheet("Sheet1")
+	f.SetActiveSheet(0)
+
+	return f.SaveAs(path)
+}
@@ -0,0 +1,259 @@
+package ui
+
+import (
+	"encoding/csv"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"tax-cli/pkg/rules"
+	"tax-cli/pkg/tax"
+)
+
+/* =======================
+   FINALIZE TUI MODEL
+======================= */
+
+type fstep int
+
+const (
+	fstepDependents fstep = iota
+	fstepInsurance
+	fstepMonths
+	fstepWithheld
+	fstepOneOffs
+	fstepResult
+)
+
+// fmodel drives the annual finalization ("quyết toán") flow: twelve months
+// of gross income, twelve months of tax already withheld, and an optional
+// set of one-off items, entered as comma-separated values to keep the flow
+// on par with the single-input-per-step style of the monthly calculator.
+type fmodel struct {
+	step       fstep
+	dependents int
+	insurance  bool
+	months     []float64
+	withheld   []float64
+	oneOffs    []tax.OneOff
+	input      textinput.Model
+	rules      rules.Rules
+	result     tax.Result
+}
+
+func initialFinalizeModel(r rules.Rules) fmodel {
+	ti := textinput.New()
+	ti.Focus()
+	ti.CharLimit = 200
+	ti.Placeholder = "Số người phụ thuộc"
+
+	return fmodel{
+		step:      fstepDependents,
+		insurance: true,
+		rules:     r,
+		input:     ti,
+	}
+}
+
+// RunTaxFinalizeTUI starts the annual PIT finalization flow using the
+// given ruleset.
+func RunTaxFinalizeTUI(r rules.Rules) error {
+	_, err := tea.NewProgram(initialFinalizeModel(r)).Run()
+	return err
+}
+
+func (m fmodel) Init() tea.Cmd {
+	return nil
+}
+
+func (m fmodel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+
+	case tea.KeyMsg:
+		switch msg.String() {
+
+		case "ctrl+c", "q":
+			return m, tea.Quit
+
+		case "up", "down":
+			if m.step == fstepInsurance {
+				m.insurance = !m.insurance
+			}
+			return m, nil
+
+		case "enter":
+			return m.next()
+		}
+	}
+
+	if m.step != fstepInsurance && m.step != fstepResult {
+		var cmd tea.Cmd
+		m.input, cmd = m.input.Update(msg)
+		return m, cmd
+	}
+
+	return m, nil
+}
+
+func (m fmodel) next() (tea.Model, tea.Cmd) {
+	switch m.step {
+
+	case fstepDependents:
+		m.dependents, _ = strconv.Atoi(m.input.Value())
+		m.step = fstepInsurance
+
+	case fstepInsurance:
+		m.step = fstepMonths
+		m.input.Placeholder = "12 tháng gross, cách nhau bởi dấu phẩy"
+		m.input.SetValue("")
+
+	case fstepMonths:
+		m.months = parseFloatList(m.input.Value())
+		m.step = fstepWithheld
+		m.input.Placeholder = "12 tháng thuế đã khấu trừ, cách nhau bởi dấu phẩy"
+		m.input.SetValue("")
+
+	case fstepWithheld:
+		m.withheld = parseFloatList(m.input.Value())
+		m.step = fstepOneOffs
+		m.input.Placeholder = "Khoản một lần: tên=số tiền;taxable, cách nhau bởi dấu phẩy"
+		m.input.SetValue("")
+
+	case fstepOneOffs:
+		m.oneOffs = parseOneOffs(m.input.Value())
+		m.result = tax.Finalize(tax.Input{
+			Months:     toMonthRecords(m.months, m.withheld),
+			OneOffs:    m.oneOffs,
+			Dependents: m.dependents,
+			Insurance:  m.insurance,
+			Rules:      m.rules,
+		})
+		m.step = fstepResult
+
+	case fstepResult:
+		exportFinalizeCSV(m.result)
+		return m, tea.Quit
+	}
+
+	return m, nil
+}
+
+func (m fmodel) View() string {
+	switch m.step {
+
+	case fstepDependents, fstepMonths, fstepWithheld, fstepOneOffs:
+		return title.Render("✏️ Quyết toán thuế năm\n\n") +
+			m.input.View() + "\n\nEnter tiếp tục"
+
+	case fstepInsurance:
+		return title.Render("🛡️ Bảo hiểm\n\n") +
+			radio("Có", m.insurance) +
+			radio("Không", !m.insurance)
+
+	case fstepResult:
+		return m.resultView()
+	}
+
+	return ""
+}
+
+func (m fmodel) resultView() string {
+	r := m.result
+
+	out := title.Render("📊 Kết quả quyết toán\n\n")
+	out += fmt.Sprintf("Tổng gross:     %.0f VND\n", r.TotalGross)
+	out += fmt.Sprintf("Khoản một lần:  %.0f VND\n", r.TotalOneOffs)
+	out += fmt.Sprintf("Thu nhập chịu thuế: %.0f VND\n", r.TotalTaxable)
+	out += fmt.Sprintf("Thuế cả năm:    %.0f VND\n", r.AnnualTax)
+	out += fmt.Sprintf("Đã khấu trừ:    %.0f VND\n\n", r.TotalWithheld)
+
+	if r.Balance >= 0 {
+		out += fmt.Sprintf("✅ Được hoàn thuế: %.0f VND\n", r.Balance)
+	} else {
+		out += fmt.Sprintf("⚠️ Phải nộp thêm: %.0f VND\n", -r.Balance)
+	}
+
+	out += "\n🧮 Chi tiết:\n"
+	for _, b := range r.Breakdown {
+		out += fmt.Sprintf(" %.0f%% → %.0f\n", b.Rate*100, b.Tax)
+	}
+
+	out += "\nEnter để xuất CSV • q để thoát"
+	return out
+}
+
+func exportFinalizeCSV(r tax.Result) {
+	file, _ := os.Create("pit-vietnam-finalize.csv")
+	defer file.Close()
+	w := csv.NewWriter(file)
+	defer w.Flush()
+
+	w.WriteAll([][]string{
+		{"TotalGross", fmt.Sprintf("%.0f", r.TotalGross)},
+		{"TotalOneOffs", fmt.Sprintf("%.0f", r.TotalOneOffs)},
+		{"TotalTaxable", fmt.Sprintf("%.0f", r.TotalTaxable)},
+		{"AnnualTax", fmt.Sprintf("%.0f", r.AnnualTax)},
+		{"TotalWithheld", fmt.Sprintf("%.0f", r.TotalWithheld)},
+		{"Balance", fmt.Sprintf("%.0f", r.Balance)},
+	})
+}
+
+/* =======================
+   PARSING HELPERS
+======================= */
+
+func parseFloatList(raw string) []float64 {
+	var out []float64
+	for _, part := range strings.Split(raw, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		v, _ := strconv.ParseFloat(part, 64)
+		out = append(out, v)
+	}
+	return out
+}
+
+// parseOneOffs parses entries like "Lương tháng 13=15000000;true,KPI=5000000;true".
+func parseOneOffs(raw string) []tax.OneOff {
+	var out []tax.OneOff
+	for _, part := range strings.Split(raw, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		nameAndRest := strings.SplitN(part, "=", 2)
+		if len(nameAndRest) != 2 {
+			continue
+		}
+		amountAndTaxable := strings.SplitN(nameAndRest[1], ";", 2)
+		amount, _ := strconv.ParseFloat(strings.TrimSpace(amountAndTaxable[0]), 64)
+		taxable := true
+		if len(amountAndTaxable) == 2 {
+			taxable, _ = strconv.ParseBool(strings.TrimSpace(amountAndTaxable[1]))
+		}
+		out = append(out, tax.OneOff{
+			Name:    strings.TrimSpace(nameAndRest[0]),
+			Amount:  amount,
+			Taxable: taxable,
+		})
+	}
+	return out
+}
+
+func toMonthRecords(gross, withheld []float64) []tax.MonthRecord {
+	out := make([]tax.MonthRecord, 0, len(gross))
+	for i, g := range gross {
+		w := 0.0
+		if i < len(withheld) {
+			w = withheld[i]
+		}
+		out = append(out, tax.MonthRecord{Month: i + 1, Gross: g, WithheldTax: w})
+	}
+	return out
+}
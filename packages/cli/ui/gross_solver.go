@@ -0,0 +1,132 @@
+package ui
+
+import (
+	"sort"
+
+	"tax-cli/pkg/rules"
+)
+
+/* =======================
+   GROSS-FROM-NET INVERSE
+======================= */
+
+// netOfGross is the forward direction of the monthly gross/net calculation:
+// the same pipeline Compute uses, without the mode branching.
+func netOfGross(gross float64, dependents int, insurance bool, r rules.Rules) float64 {
+	base := ClampInsuranceBase(gross, insurance, r.InsuranceCap)
+	ins := base * SumRates(r.EmployeeInsurance)
+	deductions := r.PersonalDeduction + float64(dependents)*r.DependentDeduction + ins
+	taxable := Max(0, gross-deductions)
+	_, tax := CalculateTax(taxable, r.TaxBrackets, Monthly)
+	return gross - ins - tax
+}
+
+// grossBreakpoints enumerates every gross value where net(gross) changes
+// slope: the insurance cap (where the insurance base stops growing with
+// gross) and, for each side of that cap, the gross value at which taxable
+// income crosses a tax bracket boundary. Between consecutive breakpoints
+// net is exactly affine in gross, so solveGrossFromNet only needs to find
+// the right interval and solve a line, never iterate.
+func grossBreakpoints(dependents int, insurance bool, r rules.Rules) []float64 {
+	fixedDeduction := r.PersonalDeduction + float64(dependents)*r.DependentDeduction
+	empRate := SumRates(r.EmployeeInsurance)
+
+	cumLimits := []float64{0}
+	for _, b := range r.TaxBrackets {
+		cumLimits = append(cumLimits, cumLimits[len(cumLimits)-1]+b.Limit)
+	}
+
+	points := []float64{0}
+
+	if !insurance {
+		// Single region: taxable = gross - fixedDeduction.
+		for _, l := range cumLimits {
+			points = append(points, l+fixedDeduction)
+		}
+		points = append(points, 1e16)
+		return sortedUnique(points)
+	}
+
+	cap := r.InsuranceCap
+	points = append(points, cap)
+
+	// Region A: gross <= cap, insurance linear in gross.
+	// taxable = gross*(1-empRate) - fixedDeduction.
+	slopeA := 1 - empRate
+	for _, l := range cumLimits {
+		g := (l + fixedDeduction) / slopeA
+		if g >= 0 && g <= cap {
+			points = append(points, g)
+		}
+	}
+
+	// Region B: gross > cap, insurance capped (constant).
+	// taxable = gross - fixedDeduction - cap*empRate.
+	interceptB := fixedDeduction + cap*empRate
+	for _, l := range cumLimits {
+		g := l + interceptB
+		if g > cap {
+			points = append(points, g)
+		}
+	}
+
+	points = append(points, 1e16)
+	return sortedUnique(points)
+}
+
+func sortedUnique(vals []float64) []float64 {
+	sort.Float64s(vals)
+	out := vals[:0]
+	for i, v := range vals {
+		if i == 0 || v != out[len(out)-1] {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+// solveGrossFromNet inverts netOfGross exactly. Because net is piecewise
+// affine in gross, it binary-searches the finite set of breakpoints for the
+// interval containing targetNet and solves the line through the interval's
+// endpoints. It falls back to bisection only as a numerical safety net in
+// case the closed-form solution lands outside the interval.
+func solveGrossFromNet(targetNet float64, dependents int, insurance bool, r rules.Rules) float64 {
+	breakpoints := grossBreakpoints(dependents, insurance, r)
+
+	i := sort.Search(len(breakpoints), func(i int) bool {
+		return netOfGross(breakpoints[i], dependents, insurance, r) >= targetNet
+	})
+
+	switch {
+	case i <= 0:
+		return breakpoints[0]
+	case i >= len(breakpoints):
+		i = len(breakpoints) - 1
+	}
+
+	lo, hi := breakpoints[i-1], breakpoints[i]
+	netLo, netHi := netOfGross(lo, dependents, insurance, r), netOfGross(hi, dependents, insurance, r)
+
+	if netHi == netLo {
+		return lo
+	}
+
+	gross := lo + (targetNet-netLo)*(hi-lo)/(netHi-netLo)
+	if gross < lo || gross > hi {
+		return bisectGross(lo, hi, targetNet, dependents, insurance, r)
+	}
+	return gross
+}
+
+// bisectGross is the numerical-safety fallback for solveGrossFromNet.
+func bisectGross(lo, hi, targetNet float64, dependents int, insurance bool, r rules.Rules) float64 {
+	for i := 0; i < 100; i++ {
+		mid := (lo + hi) / 2
+		if netOfGross(mid, dependents, insurance, r) < targetNet {
+			lo = mid
+		} else {
+			hi = mid
+		}
+	}
+	return (lo + hi) / 2
+}
@@ -0,0 +1,41 @@
+package ui
+
+import (
+	"testing"
+
+	"tax-cli/pkg/rules"
+)
+
+// TestSolveGrossFromNetRoundTrip asserts solveGrossFromNet(netOfGross(g)) == g
+// within 1 VND for gross values sampled at every breakpoint and its
+// immediate neighbors, where the old fixed-point iteration was most prone
+// to drift.
+func TestSolveGrossFromNetRoundTrip(t *testing.T) {
+	r := rules.Default()
+
+	cases := []struct {
+		dependents int
+		insurance  bool
+	}{
+		{0, true},
+		{2, true},
+		{0, false},
+		{3, false},
+	}
+
+	for _, c := range cases {
+		for _, bp := range grossBreakpoints(c.dependents, c.insurance, r) {
+			if bp <= 0 || bp >= 1e15 {
+				continue
+			}
+			for _, gross := range []float64{bp - 1, bp, bp + 1} {
+				net := netOfGross(gross, c.dependents, c.insurance, r)
+				got := solveGrossFromNet(net, c.dependents, c.insurance, r)
+				if diff := got - gross; diff > 1 || diff < -1 {
+					t.Errorf("dependents=%d insurance=%v gross=%.2f: solveGrossFromNet(netOfGross(gross))=%.2f, diff=%.4f",
+						c.dependents, c.insurance, gross, got, diff)
+				}
+			}
+		}
+	}
+}
@@ -1,73 +1,48 @@
 package ui
 
 import (
-	"encoding/csv"
 	"fmt"
 	"os"
+	"sort"
 	"strconv"
+	"strings"
 
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+
+	"tax-cli/pkg/rules"
 )
 
 /* =======================
    DOMAIN TYPES
 ======================= */
 
-type Period string
-type SalaryMode string
+// Period, TaxBreakdown, and the pure tax-math helpers below are aliases
+// for pkg/rules: pkg/tax also needs them (to annualize brackets for
+// finalization), so they live in pkg/rules, the one package both ui and
+// pkg/tax already depend on, rather than here.
+type Period = rules.Period
 
 const (
-	Monthly Period = "monthly"
-	Annual  Period = "annual"
-
-	Gross SalaryMode = "gross"
-	Net   SalaryMode = "net"
+	Monthly = rules.Monthly
+	Annual  = rules.Annual
 )
 
-type TaxBracket struct {
-	Limit float64
-	Rate  float64
-}
-
-type TaxBreakdown struct {
-	Rate    float64
-	Taxable float64
-	Tax     float64
-}
-
-/* =======================
-   CONSTANTS
-======================= */
+type SalaryMode string
 
 const (
-	PersonalDeduction  = 11_000_000
-	DependentDeduction = 4_400_000
-	InsuranceCap       = 36_000_000
+	Gross SalaryMode = "gross"
+	Net   SalaryMode = "net"
 )
 
-var EmployeeInsurance = map[string]float64{
-	"BHXH": 0.08,
-	"BHYT": 0.015,
-	"BHTN": 0.01,
-}
+// TaxBracket is an alias for rules.Bracket: the ui package used to define
+// its own bracket/deduction/insurance constants, but they now live in a
+// Rules value (see pkg/rules) threaded explicitly through the functions
+// below instead of being read off package-level globals.
+type TaxBracket = rules.Bracket
 
-var EmployerInsurance = map[string]float64{
-	"BHXH": 0.175,
-	"BHYT": 0.03,
-	"BHTN": 0.01,
-}
-
-var TaxBrackets = []TaxBracket{
-	{5_000_000, 0.05},
-	{5_000_000, 0.10},
-	{8_000_000, 0.15},
-	{14_000_000, 0.20},
-	{20_000_000, 0.25},
-	{28_000_000, 0.30},
-	{1e18, 0.35},
-}
+type TaxBreakdown = rules.TaxBreakdown
 
 /* =======================
    PURE LOGIC
@@ -80,67 +55,26 @@ func toMonthly(v float64, p Period) float64 {
 	return v
 }
 
-func sumRates(r map[string]float64) float64 {
-	s := 0.0
-	for _, v := range r {
-		s += v
-	}
-	return s
+func SumRates(r map[string]float64) float64 {
+	return rules.SumRates(r)
 }
 
-func clampInsuranceBase(gross float64, enabled bool) float64 {
-	if !enabled {
-		return 0
-	}
-	if gross > InsuranceCap {
-		return InsuranceCap
-	}
-	return gross
-}
-
-func calculateTax(taxable float64) ([]TaxBreakdown, float64) {
-	var out []TaxBreakdown
-	remain := taxable
-	total := 0.0
-
-	for _, b := range TaxBrackets {
-		if remain <= 0 {
-			break
-		}
-		apply := min(b.Limit, remain)
-		tax := apply * b.Rate
-		out = append(out, TaxBreakdown{b.Rate, apply, tax})
-		total += tax
-		remain -= apply
-	}
-	return out, total
+func ClampInsuranceBase(gross float64, enabled bool, cap float64) float64 {
+	return rules.ClampInsuranceBase(gross, enabled, cap)
 }
 
-func solveGrossFromNet(targetNet float64, dependents int, insurance bool) float64 {
-	gross := targetNet
-	for i := 0; i < 20; i++ {
-		base := clampInsuranceBase(gross, insurance)
-		ins := base * sumRates(EmployeeInsurance)
-		deductions := PersonalDeduction + float64(dependents)*DependentDeduction + ins
-		taxable := max(0, gross-deductions)
-		_, tax := calculateTax(taxable)
-		net := gross - ins - tax
-		gross += targetNet - net
-	}
-	return gross
+// CalculateTax applies brackets to taxable income for the given period.
+// Callers pass the monthly bracket table regardless of period; for Annual
+// it is transparently scaled to an annual table.
+func CalculateTax(taxable float64, brackets []TaxBracket, period Period) ([]TaxBreakdown, float64) {
+	return rules.CalculateTax(taxable, brackets, period)
 }
 
-func min(a, b float64) float64 {
-	if a < b {
-		return a
-	}
-	return b
+func Min(a, b float64) float64 {
+	return rules.Min(a, b)
 }
-func max(a, b float64) float64 {
-	if a > b {
-		return a
-	}
-	return b
+func Max(a, b float64) float64 {
+	return rules.Max(a, b)
 }
 
 /* =======================
@@ -155,6 +89,7 @@ const (
 	stepIncome
 	stepDependents
 	stepInsurance
+	stepBank
 	stepResult
 )
 
@@ -165,6 +100,9 @@ type model struct {
 	income     float64
 	dependents int
 	insurance  bool
+	bankName   string
+	bankCard   string
+	rules      rules.Rules
 	input      textinput.Model
 }
 
@@ -180,7 +118,7 @@ var dim = lipgloss.NewStyle().Foreground(lipgloss.Color("240"))
    INIT
 ======================= */
 
-func initialModel() model {
+func initialModel(r rules.Rules) model {
 	ti := textinput.New()
 	ti.Focus()
 	ti.CharLimit = 20
@@ -190,6 +128,7 @@ func initialModel() model {
 		mode:      Gross,
 		period:    Monthly,
 		insurance: true,
+		rules:     r,
 		input:     ti,
 	}
 }
@@ -198,8 +137,10 @@ func (m model) Init() tea.Cmd {
 	return nil
 }
 
-func RunTaxTUI() error {
-	_, err := tea.NewProgram(initialModel()).Run()
+// RunTaxTUI starts the interactive monthly calculator using the given
+// ruleset (brackets, deductions, insurance rates).
+func RunTaxTUI(r rules.Rules) error {
+	_, err := tea.NewProgram(initialModel(r)).Run()
 	return err
 }
 
@@ -225,7 +166,7 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 	}
 
-	if m.step == stepIncome || m.step == stepDependents {
+	if m.step == stepIncome || m.step == stepDependents || m.step == stepBank {
 		var cmd tea.Cmd
 		m.input, cmd = m.input.Update(msg)
 		return m, cmd
@@ -275,10 +216,22 @@ func (m model) next() (tea.Model, tea.Cmd) {
 		m.step = stepInsurance
 
 	case stepInsurance:
+		m.step = stepBank
+		m.input.Placeholder = "Ngân hàng,Số tài khoản"
+		m.input.SetValue("")
+
+	case stepBank:
+		bank := strings.SplitN(m.input.Value(), ",", 2)
+		m.bankName = strings.TrimSpace(bank[0])
+		if len(bank) == 2 {
+			m.bankCard = strings.TrimSpace(bank[1])
+		}
 		m.step = stepResult
 
 	case stepResult:
-		exportCSV(m)
+		if err := exportXLSX(m); err != nil {
+			fmt.Fprintln(os.Stderr, "lỗi xuất Excel:", err)
+		}
 		return m, tea.Quit
 	}
 
@@ -302,7 +255,7 @@ func (m model) View() string {
 			radio("Tháng", m.period == Monthly) +
 			radio("Năm", m.period == Annual)
 
-	case stepIncome, stepDependents:
+	case stepIncome, stepDependents, stepBank:
 		return title.Render("✏️ Nhập dữ liệu\n\n") +
 			m.input.View() + "\n\nEnter tiếp tục"
 
@@ -326,46 +279,91 @@ func radio(label string, on bool) string {
 }
 
 /* =======================
-   RESULT + CSV
+   RESULT
 ======================= */
 
-func (m model) resultView() string {
+// CalcResult is the full output of a gross/net calculation, including the
+// insurance line items needed for payroll export (per-contribution base and
+// amount for both employee and employer sides).
+type CalcResult struct {
+	Gross             float64
+	Net               float64
+	Tax               float64
+	Dependents        int
+	Breakdown         []TaxBreakdown
+	EmployeeInsurance []InsuranceRow
+	EmployerInsurance []InsuranceRow
+}
+
+// InsuranceRow is one BHXH/BHYT/BHTN line, clamped to the ruleset's insurance cap.
+type InsuranceRow struct {
+	Name   string
+	Rate   float64
+	Base   float64
+	Amount float64
+}
+
+// insuranceRows builds one row per contribution, in sorted name order so
+// the result (and anything downstream: the Excel "Insurance" sheet, the
+// --json employeeInsurance/employerInsurance arrays) is reproducible
+// run-to-run instead of following Go's randomized map iteration order.
+func insuranceRows(rates map[string]float64, base float64) []InsuranceRow {
+	names := make([]string, 0, len(rates))
+	for name := range rates {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	out := make([]InsuranceRow, 0, len(rates))
+	for _, name := range names {
+		rate := rates[name]
+		out = append(out, InsuranceRow{Name: name, Rate: rate, Base: base, Amount: base * rate})
+	}
+	return out
+}
+
+// Compute runs the gross/net PIT calculation shared by the interactive TUI
+// and the batch/non-interactive code paths, under the given ruleset.
+func Compute(mode SalaryMode, period Period, income float64, dependents int, insurance bool, r rules.Rules) CalcResult {
 	var gross float64
-	if m.mode == Gross {
-		gross = toMonthly(m.income, m.period)
+	if mode == Gross {
+		gross = toMonthly(income, period)
 	} else {
-		gross = solveGrossFromNet(m.income, m.dependents, m.insurance)
+		gross = solveGrossFromNet(income, dependents, insurance, r)
 	}
 
-	base := clampInsuranceBase(gross, m.insurance)
-	empIns := base * sumRates(EmployeeInsurance)
+	base := ClampInsuranceBase(gross, insurance, r.InsuranceCap)
+	empIns := base * SumRates(r.EmployeeInsurance)
 
-	deductions := PersonalDeduction + float64(m.dependents)*DependentDeduction + empIns
-	taxable := max(0, gross-deductions)
-	breakdown, tax := calculateTax(taxable)
+	deductions := r.PersonalDeduction + float64(dependents)*r.DependentDeduction + empIns
+	taxable := Max(0, gross-deductions)
+	breakdown, tax := CalculateTax(taxable, r.TaxBrackets, Monthly)
 	net := gross - empIns - tax
 
+	return CalcResult{
+		Gross:             gross,
+		Net:               net,
+		Tax:               tax,
+		Dependents:        dependents,
+		Breakdown:         breakdown,
+		EmployeeInsurance: insuranceRows(r.EmployeeInsurance, base),
+		EmployerInsurance: insuranceRows(r.EmployerInsurance, base),
+	}
+}
+
+func (m model) resultView() string {
+	r := Compute(m.mode, m.period, m.income, m.dependents, m.insurance, m.rules)
+
 	out := title.Render("📊 Kết quả\n\n")
-	out += fmt.Sprintf("Gross: %.0f VND\n", gross)
-	out += fmt.Sprintf("Net:   %.0f VND\n", net)
-	out += fmt.Sprintf("Tax:   %.0f VND\n\n", tax)
+	out += fmt.Sprintf("Gross: %.0f VND\n", r.Gross)
+	out += fmt.Sprintf("Net:   %.0f VND\n", r.Net)
+	out += fmt.Sprintf("Tax:   %.0f VND\n\n", r.Tax)
 
 	out += "🧮 Chi tiết:\n"
-	for _, b := range breakdown {
+	for _, b := range r.Breakdown {
 		out += fmt.Sprintf(" %.0f%% → %.0f\n", b.Rate*100, b.Tax)
 	}
 
-	out += "\nEnter để xuất CSV • q để thoát"
+	out += "\nEnter để xuất Excel • q để thoát"
 	return out
 }
-
-func exportCSV(m model) {
-	file, _ := os.Create("pit-vietnam.csv")
-	defer file.Close()
-	w := csv.NewWriter(file)
-	defer w.Flush()
-
-	w.WriteAll([][]string{
-		{"Gross", fmt.Sprintf("%.0f", m.income)},
-	})
-}